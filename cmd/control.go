@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/0glabs/0g-storage-client/common/shard"
+	"github.com/0glabs/0g-storage-client/common/shard/registry"
+	"github.com/spf13/cobra"
+)
+
+var controlArgs struct {
+	registryFile string
+	numShard     uint64
+	shardId      uint64
+	force        bool
+	segNum       uint64
+	expectedRep  uint
+}
+
+var controlCmd = &cobra.Command{
+	Use:   "control",
+	Short: "Add, remove, drain or list the sharded nodes used by shard.Select at runtime",
+}
+
+var controlAddNodeCmd = &cobra.Command{
+	Use:   "add-node <url>",
+	Short: "Register a node",
+	Args:  cobra.ExactArgs(1),
+	RunE:  controlAddNode,
+}
+
+var controlRemoveNodeCmd = &cobra.Command{
+	Use:   "remove-node <url>",
+	Short: "Unregister a node, refusing if it would under-replicate a shard",
+	Args:  cobra.ExactArgs(1),
+	RunE:  controlRemoveNode,
+}
+
+var controlDrainNodeCmd = &cobra.Command{
+	Use:   "drain-node <url>",
+	Short: "Mark a node ineligible for new selections, letting in-flight uploads finish",
+	Args:  cobra.ExactArgs(1),
+	RunE:  controlDrainNode,
+}
+
+var controlListNodesCmd = &cobra.Command{
+	Use:   "list-nodes",
+	Short: "List registered nodes as JSON",
+	RunE:  controlListNodes,
+}
+
+func init() {
+	controlCmd.PersistentFlags().StringVar(&controlArgs.registryFile, "registry-file", "nodes.json", "Path to the node registry file")
+
+	controlAddNodeCmd.Flags().Uint64Var(&controlArgs.numShard, "num-shard", 1, "Node's NumShard config")
+	controlAddNodeCmd.Flags().Uint64Var(&controlArgs.shardId, "shard-id", 0, "Node's ShardId config")
+
+	controlRemoveNodeCmd.Flags().BoolVar(&controlArgs.force, "force", false, "Remove the node even if it would drop a shard below the expected replica count")
+	controlRemoveNodeCmd.Flags().Uint64Var(&controlArgs.segNum, "seg-num", 0, "Total number of segments to check replication for")
+	controlRemoveNodeCmd.Flags().UintVar(&controlArgs.expectedRep, "expected-replica", 1, "Expected replica count per shard")
+
+	controlCmd.AddCommand(controlAddNodeCmd, controlRemoveNodeCmd, controlDrainNodeCmd, controlListNodesCmd)
+	rootCmd.AddCommand(controlCmd)
+}
+
+func printJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func controlAddNode(_ *cobra.Command, args []string) error {
+	url := args[0]
+	node := &shard.ShardedNode{
+		URL: url,
+		Config: shard.ShardConfig{
+			NumShard: controlArgs.numShard,
+			ShardId:  controlArgs.shardId,
+		},
+	}
+
+	if err := registry.Open(controlArgs.registryFile).AddNode(node); err != nil {
+		return err
+	}
+
+	return printJSON(map[string]interface{}{"added": url})
+}
+
+func controlRemoveNode(_ *cobra.Command, args []string) error {
+	url := args[0]
+	reg := registry.Open(controlArgs.registryFile)
+
+	if err := reg.RemoveNode(url, controlArgs.segNum, controlArgs.expectedRep, controlArgs.force); err != nil {
+		return err
+	}
+
+	return printJSON(map[string]interface{}{"removed": url})
+}
+
+func controlDrainNode(_ *cobra.Command, args []string) error {
+	url := args[0]
+
+	if err := registry.Open(controlArgs.registryFile).DrainNode(url); err != nil {
+		return err
+	}
+
+	return printJSON(map[string]interface{}{"drained": url})
+}
+
+func controlListNodes(*cobra.Command, []string) error {
+	states, err := registry.Open(controlArgs.registryFile).List()
+	if err != nil {
+		return err
+	}
+
+	return printJSON(states)
+}
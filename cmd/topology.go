@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/0glabs/0g-storage-client/common/shard"
+	"github.com/0glabs/0g-storage-client/common/shard/monitor"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var topologyArgs struct {
+	urls             string
+	expectedReplica  uint
+	segNum           uint64
+	probeInterval    time.Duration
+	debounceInterval time.Duration
+}
+
+var topologyCmd = &cobra.Command{
+	Use:   "topology",
+	Short: "Inspect and watch the live shard topology of a storage node set",
+}
+
+var topologyWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Continuously probe the node set and print topology change events",
+	RunE:  topologyWatch,
+}
+
+var topologyCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Probe the node set once and report which shard ids are under-replicated",
+	RunE:  topologyCheck,
+}
+
+func init() {
+	topologyCmd.PersistentFlags().StringVar(&topologyArgs.urls, "urls", "", "Comma separated list of storage node URLs to monitor")
+	topologyCmd.MarkPersistentFlagRequired("urls")
+	topologyCmd.PersistentFlags().DurationVar(&topologyArgs.probeInterval, "probe-interval", 30*time.Second, "Interval between topology probes")
+	topologyCmd.PersistentFlags().DurationVar(&topologyArgs.debounceInterval, "debounce-interval", 0, "Minimum interval since a node's last event before a failed probe removes it (defaults to probe-interval)")
+
+	topologyCheckCmd.Flags().UintVar(&topologyArgs.expectedReplica, "expected-replica", 1, "Expected replica count per shard")
+	topologyCheckCmd.Flags().Uint64Var(&topologyArgs.segNum, "seg-num", 0, "Total number of segments to check replication for")
+	topologyCheckCmd.MarkFlagRequired("seg-num")
+
+	topologyCmd.AddCommand(topologyWatchCmd, topologyCheckCmd)
+	rootCmd.AddCommand(topologyCmd)
+}
+
+func topologyMonitor() *monitor.Monitor {
+	urls := strings.Split(topologyArgs.urls, ",")
+	config := monitor.Config{
+		ProbeInterval:    topologyArgs.probeInterval,
+		DebounceInterval: topologyArgs.debounceInterval,
+	}
+	return monitor.New(urls, probeNode, nil, config)
+}
+
+// probeNode measures RPC latency to a storage node and reports its shard
+// config.
+func probeNode(ctx context.Context, url string) (*shard.ShardedNode, error) {
+	start := time.Now()
+
+	config, err := fetchShardConfig(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("probing %s: %w", url, err)
+	}
+
+	return &shard.ShardedNode{
+		URL:     url,
+		Config:  *config,
+		Latency: time.Since(start).Milliseconds(),
+		Since:   time.Now().Unix(),
+	}, nil
+}
+
+// fetchShardConfig calls the storage node's zgs_getShardConfig JSON-RPC
+// method to read its NumShard/ShardId.
+func fetchShardConfig(ctx context.Context, url string) (*shard.ShardConfig, error) {
+	reqBody, err := json.Marshal(struct {
+		JSONRPC string `json:"jsonrpc"`
+		ID      int    `json:"id"`
+		Method  string `json:"method"`
+	}{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "zgs_getShardConfig",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var rpcResp struct {
+		Result *shard.ShardConfig `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return nil, fmt.Errorf("node returned error: %s", rpcResp.Error.Message)
+	}
+	if rpcResp.Result == nil {
+		return nil, fmt.Errorf("node returned no shard config")
+	}
+
+	return rpcResp.Result, nil
+}
+
+func topologyWatch(*cobra.Command, []string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	events := topologyMonitor().Watch(ctx)
+	for event := range events {
+		logrus.WithFields(logrus.Fields{
+			"kind": event.Kind,
+			"node": event.Node,
+		}).Info("Topology event")
+	}
+
+	return nil
+}
+
+func topologyCheck(*cobra.Command, []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), topologyArgs.probeInterval)
+	defer cancel()
+
+	m := topologyMonitor()
+	events := m.Watch(ctx)
+	for range events {
+	}
+
+	nodes := m.Snapshot()
+	configs := make([]*shard.ShardConfig, len(nodes))
+	for i, node := range nodes {
+		configs[i] = &node.Config
+	}
+
+	ok := shard.CheckReplica(topologyArgs.segNum, configs, topologyArgs.expectedReplica)
+
+	_, report, _ := shard.SelectWeighted(topologyArgs.segNum, nodes, topologyArgs.expectedReplica, shard.SelectionPolicy{
+		BestEffortDegrade: true,
+	})
+
+	out, err := json.Marshal(map[string]interface{}{
+		"ok":              ok,
+		"underReplicated": report,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	fmt.Println(string(out))
+	return nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/0glabs/0g-storage-client/common/pubsub"
+	"github.com/0glabs/0g-storage-client/common/shard"
+	"github.com/0glabs/0g-storage-client/common/shard/registry"
+	"github.com/0glabs/0g-storage-client/core"
+	"github.com/0glabs/0g-storage-client/core/merkle"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/cobra"
+)
+
+var uploadArgs struct {
+	file            string
+	coordinator     string
+	group           string
+	registryFile    string
+	expectedReplica uint
+}
+
+var uploadCmd = &cobra.Command{
+	Use:   "upload",
+	Short: "Upload file to ZeroGStorage network",
+	RunE:  upload,
+}
+
+func init() {
+	uploadCmd.Flags().StringVar(&uploadArgs.file, "file", "", "File name to upload. With --coordinator, this path must be reachable under the same path by every worker process (e.g. shared/network storage), since workers read the file's bytes directly rather than having them sent over the coordinator stream")
+	uploadCmd.MarkFlagRequired("file")
+
+	uploadCmd.Flags().StringVar(&uploadArgs.coordinator, "coordinator", "", "Redis URL of a distributed upload coordinator, e.g. redis://localhost:6379. Segments are dispatched to `worker` processes; without a coordinator the command returns an error, as uploading from this process is not supported")
+	uploadCmd.Flags().StringVar(&uploadArgs.group, "coordinator-group", "uploaders", "Consumer group name for the distributed coordinator")
+	uploadCmd.Flags().StringVar(&uploadArgs.registryFile, "registry-file", "nodes.json", "Path to the node registry file (see the control command)")
+	uploadCmd.Flags().UintVar(&uploadArgs.expectedReplica, "expected-replica", 1, "Expected replica count per shard")
+
+	rootCmd.AddCommand(uploadCmd)
+}
+
+func upload(*cobra.Command, []string) error {
+	if uploadArgs.coordinator == "" {
+		return fmt.Errorf("local (non-coordinated) upload is not available in this build")
+	}
+	return uploadViaCoordinator()
+}
+
+// uploadViaCoordinator computes the file's merkle root and the sharded nodes
+// that should receive it, then publishes one pubsub.WorkItem per
+// (segment, shard) onto the coordinator stream, so that any number of
+// `worker` processes can pick them up and perform the actual uploads.
+func uploadViaCoordinator() error {
+	opt, err := redis.ParseURL(uploadArgs.coordinator)
+	if err != nil {
+		return fmt.Errorf("invalid coordinator URL: %w", err)
+	}
+	client := redis.NewClient(opt)
+	defer client.Close()
+
+	root, segNum, err := fileRootAndSegments(uploadArgs.file)
+	if err != nil {
+		return fmt.Errorf("failed to compute file root: %w", err)
+	}
+
+	active, err := registry.Open(uploadArgs.registryFile).Active()
+	if err != nil {
+		return fmt.Errorf("failed to load node registry: %w", err)
+	}
+
+	nodes, ok := shard.Select(segNum, active, uploadArgs.expectedReplica, false)
+	if !ok {
+		return fmt.Errorf("registered nodes in %s cannot satisfy the expected replica count of %d", uploadArgs.registryFile, uploadArgs.expectedReplica)
+	}
+
+	ctx := context.Background()
+	producer, err := pubsub.NewProducer(ctx, client, root, pubsub.Config{Group: uploadArgs.group})
+	if err != nil {
+		return fmt.Errorf("failed to create coordinator producer: %w", err)
+	}
+
+	return dispatchWorkItems(ctx, producer, root, segNum, uploadArgs.file, nodes)
+}
+
+// fileRootAndSegments reads the file segment by segment and returns its
+// merkle root together with its (unpadded) segment count, without buffering
+// the whole file in memory.
+func fileRootAndSegments(path string) (common.Hash, uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return common.Hash{}, 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return common.Hash{}, 0, err
+	}
+
+	segNum := core.NumSplits(info.Size(), core.DefaultSegmentSize)
+	tree := merkle.NewCompactTree()
+	buf := make([]byte, core.DefaultSegmentSize)
+
+	for i := uint64(0); i < segNum; i++ {
+		n, err := f.ReadAt(buf, int64(i)*int64(core.DefaultSegmentSize))
+		if err != nil && err != io.EOF {
+			return common.Hash{}, 0, err
+		}
+		for j := n; j < len(buf); j++ {
+			buf[j] = 0
+		}
+
+		if err := tree.Append(core.SegmentRoot(buf)); err != nil {
+			return common.Hash{}, 0, err
+		}
+	}
+
+	emptySegmentHash := core.SegmentRoot(nil, uint64(core.DefaultSegmentMaxChunks))
+	return tree.Root(emptySegmentHash), segNum, nil
+}
+
+// dispatchWorkItems publishes a work item for every segment index each
+// selected node's shard covers.
+func dispatchWorkItems(ctx context.Context, producer *pubsub.Producer, root common.Hash, segNum uint64, filePath string, nodes []*shard.ShardedNode) error {
+	for _, node := range nodes {
+		for segmentIndex := node.Config.ShardId; segmentIndex < segNum; segmentIndex += node.Config.NumShard {
+			item := pubsub.WorkItem{
+				SegmentRoot:  root,
+				SegmentIndex: segmentIndex,
+				ShardId:      node.Config.ShardId,
+				NodeURL:      node.URL,
+				FilePath:     filePath,
+			}
+			if err := producer.Publish(ctx, item); err != nil {
+				return fmt.Errorf("failed to publish work item for segment %d on node %s: %w", segmentIndex, node.URL, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/0glabs/0g-storage-client/common/pubsub"
+	"github.com/0glabs/0g-storage-client/core"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var workerArgs struct {
+	coordinator string
+	group       string
+	name        string
+	root        string
+}
+
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "Join a distributed upload coordinator as a consumer and upload its work items",
+	RunE:  worker,
+}
+
+func init() {
+	workerCmd.Flags().StringVar(&workerArgs.coordinator, "coordinator", "", "Redis URL of the distributed upload coordinator, e.g. redis://localhost:6379")
+	workerCmd.MarkFlagRequired("coordinator")
+
+	workerCmd.Flags().StringVar(&workerArgs.root, "root", "", "Merkle root of the file being uploaded, identifies the coordinator stream to join")
+	workerCmd.MarkFlagRequired("root")
+
+	workerCmd.Flags().StringVar(&workerArgs.group, "group", "uploaders", "Consumer group name shared by all workers of this upload")
+	workerCmd.Flags().StringVar(&workerArgs.name, "name", "", "Consumer name, must be unique within the group (defaults to hostname-pid)")
+
+	rootCmd.AddCommand(workerCmd)
+}
+
+func worker(*cobra.Command, []string) error {
+	opt, err := redis.ParseURL(workerArgs.coordinator)
+	if err != nil {
+		return fmt.Errorf("invalid coordinator URL: %w", err)
+	}
+	client := redis.NewClient(opt)
+	defer client.Close()
+
+	name := workerArgs.name
+	if name == "" {
+		hostname, _ := os.Hostname()
+		name = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+
+	root := common.HexToHash(workerArgs.root)
+	consumer := pubsub.NewConsumer(client, root, name, pubsub.Config{
+		Group: workerArgs.group,
+	}, uploadWorkItem)
+
+	logrus.WithFields(logrus.Fields{"root": root, "group": workerArgs.group, "name": name}).Info("Joining consumer group")
+
+	return consumer.Run(context.Background())
+}
+
+// uploadWorkItem reads the segment's bytes from the shared file at
+// item.FilePath and uploads them to the node at item.NodeURL.
+func uploadWorkItem(ctx context.Context, item pubsub.WorkItem) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"segmentIndex": item.SegmentIndex,
+		"shardId":      item.ShardId,
+		"node":         item.NodeURL,
+	})
+	logger.Info("Uploading segment")
+
+	f, err := os.Open(item.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", item.FilePath, err)
+	}
+	defer f.Close()
+
+	buf := make([]byte, core.DefaultSegmentSize)
+	n, err := f.ReadAt(buf, int64(item.SegmentIndex)*int64(core.DefaultSegmentSize))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to read segment %d from %s: %w", item.SegmentIndex, item.FilePath, err)
+	}
+	for i := n; i < len(buf); i++ {
+		buf[i] = 0
+	}
+
+	if err := uploadSegment(ctx, item.NodeURL, item.SegmentRoot, item.SegmentIndex, buf); err != nil {
+		return fmt.Errorf("failed to upload segment %d to %s: %w", item.SegmentIndex, item.NodeURL, err)
+	}
+
+	logger.Info("Segment uploaded")
+	return nil
+}
+
+// uploadSegment calls the storage node's zgs_uploadSegment JSON-RPC method
+// to upload a single segment's bytes.
+func uploadSegment(ctx context.Context, url string, root common.Hash, segmentIndex uint64, data []byte) error {
+	reqBody, err := json.Marshal(struct {
+		JSONRPC string        `json:"jsonrpc"`
+		ID      int           `json:"id"`
+		Method  string        `json:"method"`
+		Params  []interface{} `json:"params"`
+	}{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "zgs_uploadSegment",
+		Params: []interface{}{map[string]interface{}{
+			"root":  root.Hex(),
+			"index": segmentIndex,
+			"data":  base64.StdEncoding.EncodeToString(data),
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var rpcResp struct {
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("node returned error: %s", rpcResp.Error.Message)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// UploadFunc performs the actual upload of a single work item to its target
+// node. It must be safe to call more than once for the same WorkItem.
+type UploadFunc func(ctx context.Context, item WorkItem) error
+
+// Consumer joins a consumer group and processes work items from a single
+// upload's stream until its context is canceled.
+type Consumer struct {
+	client *redis.Client
+	stream string
+	group  string
+	name   string
+	config Config
+	upload UploadFunc
+}
+
+// NewConsumer creates a Consumer identified by name within config.Group. Name
+// must be unique among the group's members, e.g. a hostname plus pid.
+func NewConsumer(client *redis.Client, root common.Hash, name string, config Config, upload UploadFunc) *Consumer {
+	config.setDefaults()
+	return &Consumer{
+		client: client,
+		stream: StreamKey(root),
+		group:  config.Group,
+		name:   name,
+		config: config,
+		upload: upload,
+	}
+}
+
+// Run reads and uploads work items, periodically reclaiming items abandoned
+// by dead consumers, until ctx is canceled.
+func (c *Consumer) Run(ctx context.Context) error {
+	for {
+		if err := c.reclaim(ctx); err != nil {
+			logrus.WithError(err).Warn("Failed to reclaim abandoned work items")
+		}
+
+		streams, err := c.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    c.group,
+			Consumer: c.name,
+			Streams:  []string{c.stream, ">"},
+			Count:    10,
+			Block:    c.config.VisibilityTimeout / 2,
+		}).Result()
+
+		switch {
+		case errors.Is(err, redis.Nil):
+			continue
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to read from consumer group: %w", err)
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				if err := c.process(ctx, msg); err != nil {
+					logrus.WithError(err).WithField("id", msg.ID).Error("Failed to process work item")
+				}
+			}
+		}
+	}
+}
+
+func (c *Consumer) process(ctx context.Context, msg redis.XMessage) error {
+	item, err := decode(msg)
+	if err != nil {
+		// malformed message: ack it so it doesn't block the stream forever
+		return errors.Join(err, c.client.XAck(ctx, c.stream, c.group, msg.ID).Err())
+	}
+
+	// Hold the dedup key for the duration of the upload attempt so a
+	// re-delivery of the same item (e.g. via reclaim) that arrives while
+	// we're still uploading, or after we've already succeeded, is acked
+	// without being uploaded again. The key expires twice VisibilityTimeout
+	// after it was acquired; a re-delivery after that is uploaded again.
+	acquired, err := c.client.SetNX(ctx, item.dedupKey(), c.name, c.config.VisibilityTimeout*2).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check idempotency key for %s: %w", item.idempotencyKey(), err)
+	}
+	if !acquired {
+		return c.client.XAck(ctx, c.stream, c.group, msg.ID).Err()
+	}
+
+	if err := c.upload(ctx, item); err != nil {
+		// release so a retry (reclaim, or a later delivery) can actually
+		// re-attempt the upload instead of being permanently deduped away
+		c.client.Del(ctx, item.dedupKey())
+		return fmt.Errorf("failed to upload work item %s: %w", item.idempotencyKey(), err)
+	}
+
+	return c.client.XAck(ctx, c.stream, c.group, msg.ID).Err()
+}
+
+// reclaim steals work items that have been pending for longer than
+// VisibilityTimeout, e.g. because the consumer that claimed them died.
+func (c *Consumer) reclaim(ctx context.Context) error {
+	cursor := "0-0"
+	for {
+		msgs, next, err := c.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   c.stream,
+			Group:    c.group,
+			Consumer: c.name,
+			MinIdle:  c.config.VisibilityTimeout,
+			Start:    cursor,
+			Count:    50,
+		}).Result()
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range msgs {
+			if err := c.process(ctx, msg); err != nil {
+				logrus.WithError(err).WithField("id", msg.ID).Error("Failed to process reclaimed work item")
+			}
+		}
+
+		if next == "0-0" || len(msgs) == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+func decode(msg redis.XMessage) (WorkItem, error) {
+	raw, ok := msg.Values["item"].(string)
+	if !ok {
+		return WorkItem{}, errors.New("malformed work item message: missing item field")
+	}
+
+	var item WorkItem
+	if err := json.Unmarshal([]byte(raw), &item); err != nil {
+		return WorkItem{}, fmt.Errorf("failed to unmarshal work item: %w", err)
+	}
+
+	return item, nil
+}
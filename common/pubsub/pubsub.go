@@ -0,0 +1,110 @@
+// Package pubsub implements a Redis Streams-backed work queue of segment
+// upload tasks, shared by a producer and any number of consumer workers
+// within a consumer group.
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
+)
+
+// WorkItem describes a single (segment, shard) upload task.
+type WorkItem struct {
+	SegmentRoot  common.Hash `json:"segmentRoot"`
+	SegmentIndex uint64      `json:"segmentIndex"`
+	ShardId      uint64      `json:"shardId"`
+	NodeURL      string      `json:"nodeUrl"`
+	// FilePath is the path to the file being uploaded, as seen by the
+	// producer. A worker reads the segment's bytes from this same path
+	// itself rather than having them embedded in the work item, so it must
+	// be reachable under this exact path on every worker host (e.g. shared
+	// or network storage) for a distributed upload to work.
+	FilePath string `json:"filePath"`
+}
+
+// idempotencyKey uniquely identifies a WorkItem so re-deliveries (e.g. after
+// claim recovery) don't result in a duplicate upload. Consumer enforces this
+// via dedupKey, so re-delivered items are acked without being re-uploaded.
+func (item WorkItem) idempotencyKey() string {
+	return fmt.Sprintf("%s:%d:%d:%s", item.SegmentRoot, item.SegmentIndex, item.ShardId, item.NodeURL)
+}
+
+// dedupKey returns the Redis key a Consumer holds for the duration of an
+// upload attempt, preventing a re-delivery of the same WorkItem from being
+// uploaded concurrently or again after it already succeeded. The key expires
+// twice VisibilityTimeout after the attempt started.
+func (item WorkItem) dedupKey() string {
+	return fmt.Sprintf("0g-storage-client:upload:dedup:%s", item.idempotencyKey())
+}
+
+// StreamKey returns the Redis stream key used to coordinate uploads of the
+// file identified by root.
+func StreamKey(root common.Hash) string {
+	return fmt.Sprintf("0g-storage-client:upload:%s", root.Hex())
+}
+
+// Config customizes how a Producer/Consumer pair uses Redis.
+type Config struct {
+	// Group is the consumer group name shared by all workers of an upload.
+	Group string
+	// VisibilityTimeout is how long a work item may stay claimed by a
+	// consumer before another consumer is allowed to steal it via XAUTOCLAIM.
+	VisibilityTimeout time.Duration
+}
+
+func (config *Config) setDefaults() {
+	if config.Group == "" {
+		config.Group = "uploaders"
+	}
+	if config.VisibilityTimeout == 0 {
+		config.VisibilityTimeout = 30 * time.Second
+	}
+}
+
+// Producer publishes WorkItems for a single upload onto its Redis stream.
+type Producer struct {
+	client *redis.Client
+	stream string
+	config Config
+}
+
+// NewProducer creates the consumer group for root's stream (if it doesn't
+// already exist) and returns a Producer that publishes onto it.
+func NewProducer(ctx context.Context, client *redis.Client, root common.Hash, config Config) (*Producer, error) {
+	config.setDefaults()
+	stream := StreamKey(root)
+
+	if err := client.XGroupCreateMkStream(ctx, stream, config.Group, "0").Err(); err != nil && !isBusyGroupErr(err) {
+		return nil, fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
+	return &Producer{client: client, stream: stream, config: config}, nil
+}
+
+// Publish adds a work item to the stream. Idempotency is derived from the
+// item's SegmentRoot/SegmentIndex/ShardId/NodeURL, so a work item that gets
+// re-delivered to a consumer (e.g. after claim recovery) is safe to apply
+// more than once.
+func (p *Producer) Publish(ctx context.Context, item WorkItem) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("failed to marshal work item: %w", err)
+	}
+
+	return p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		Values: map[string]interface{}{
+			"key":  item.idempotencyKey(),
+			"item": data,
+		},
+	}).Err()
+}
+
+func isBusyGroupErr(err error) bool {
+	return err != nil && err.Error() == "BUSYGROUP Consumer Group name already exists"
+}
@@ -0,0 +1,205 @@
+// Package monitor continuously tails a set of storage nodes and maintains a
+// live, debounced snapshot of their shard topology.
+package monitor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0glabs/0g-storage-client/common/shard"
+	"github.com/sirupsen/logrus"
+)
+
+// EventKind identifies what changed about a node in a TopologyEvent.
+type EventKind int
+
+const (
+	EventAdd EventKind = iota
+	EventRemove
+	EventUpdate
+)
+
+// TopologyEvent describes a single change to the monitored node set.
+type TopologyEvent struct {
+	Kind EventKind
+	Node *shard.ShardedNode
+}
+
+// Prober probes a single node and returns its current state, e.g. its shard
+// config, latency and Since timestamp. A non-nil error marks the node
+// unreachable for this probe round.
+type Prober func(ctx context.Context, url string) (*shard.ShardedNode, error)
+
+// Store persists the latest known topology snapshot so Monitor can resume
+// without losing Since history across restarts.
+type Store interface {
+	Load() ([]*shard.ShardedNode, error)
+	Save([]*shard.ShardedNode) error
+}
+
+// Config customizes Monitor's probing behavior.
+type Config struct {
+	// ProbeInterval is how often every known node URL is re-probed.
+	ProbeInterval time.Duration
+	// DebounceInterval keeps a known node whose probe fails within this
+	// window of its last event, instead of removing it and emitting
+	// EventRemove, to avoid flapping on transient failures. Add and update
+	// events are not debounced.
+	DebounceInterval time.Duration
+	// Workers bounds how many probes run concurrently.
+	Workers int
+}
+
+func (config *Config) setDefaults() {
+	if config.ProbeInterval == 0 {
+		config.ProbeInterval = 30 * time.Second
+	}
+	if config.DebounceInterval == 0 {
+		config.DebounceInterval = config.ProbeInterval
+	}
+	if config.Workers == 0 {
+		config.Workers = 8
+	}
+}
+
+// Monitor tails a fixed set of node URLs via periodic probes and maintains a
+// live snapshot of their shard topology.
+type Monitor struct {
+	urls   []string
+	probe  Prober
+	store  Store
+	config Config
+
+	mu        sync.RWMutex
+	nodes     map[string]*shard.ShardedNode
+	lastEvent map[string]time.Time
+
+	events chan TopologyEvent
+}
+
+// New creates a Monitor for the given node URLs. store may be nil, in which
+// case the topology is not persisted across restarts.
+func New(urls []string, probe Prober, store Store, config Config) *Monitor {
+	config.setDefaults()
+
+	m := &Monitor{
+		urls:      urls,
+		probe:     probe,
+		store:     store,
+		config:    config,
+		nodes:     make(map[string]*shard.ShardedNode),
+		lastEvent: make(map[string]time.Time),
+		events:    make(chan TopologyEvent, 64),
+	}
+
+	if store != nil {
+		if nodes, err := store.Load(); err == nil {
+			for _, node := range nodes {
+				m.nodes[node.URL] = node
+			}
+		}
+	}
+
+	return m
+}
+
+// Watch starts probing and returns a channel of topology events. The channel
+// is closed once ctx is canceled.
+func (m *Monitor) Watch(ctx context.Context) <-chan TopologyEvent {
+	go m.run(ctx)
+	return m.events
+}
+
+// Snapshot returns the current known node set.
+func (m *Monitor) Snapshot() []*shard.ShardedNode {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	nodes := make([]*shard.ShardedNode, 0, len(m.nodes))
+	for _, node := range m.nodes {
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+func (m *Monitor) run(ctx context.Context) {
+	defer close(m.events)
+
+	ticker := time.NewTicker(m.config.ProbeInterval)
+	defer ticker.Stop()
+
+	m.probeAll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.probeAll(ctx)
+		}
+	}
+}
+
+func (m *Monitor) probeAll(ctx context.Context) {
+	sem := make(chan struct{}, m.config.Workers)
+	var wg sync.WaitGroup
+
+	for _, url := range m.urls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(url string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			m.probeOne(ctx, url)
+		}(url)
+	}
+
+	wg.Wait()
+
+	if m.store != nil {
+		if err := m.store.Save(m.Snapshot()); err != nil {
+			logrus.WithError(err).Warn("Failed to persist shard topology snapshot")
+		}
+	}
+}
+
+func (m *Monitor) probeOne(ctx context.Context, url string) {
+	node, err := m.probe(ctx, url)
+
+	m.mu.Lock()
+	existing, known := m.nodes[url]
+	now := time.Now()
+
+	var kind EventKind
+	var emit bool
+	reported := node
+	switch {
+	case err != nil && known:
+		if now.Sub(m.lastEvent[url]) < m.config.DebounceInterval {
+			m.mu.Unlock()
+			return
+		}
+		delete(m.nodes, url)
+		reported = existing
+		kind, emit = EventRemove, true
+	case err != nil:
+		// never seen and still unreachable: nothing to report
+	case !known:
+		m.nodes[url] = node
+		kind, emit = EventAdd, true
+	default:
+		m.nodes[url] = node
+		if existing.Config != node.Config || existing.Latency != node.Latency {
+			kind, emit = EventUpdate, true
+		}
+	}
+
+	if emit {
+		m.lastEvent[url] = now
+	}
+	m.mu.Unlock()
+
+	if emit {
+		m.events <- TopologyEvent{Kind: kind, Node: reported}
+	}
+}
@@ -0,0 +1,165 @@
+// Package registry persists the set of sharded nodes that shard.Select
+// operates over in a simple JSON file.
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/0glabs/0g-storage-client/common/shard"
+)
+
+// NodeState is a registered node plus its operator-controlled eligibility.
+type NodeState struct {
+	Node *shard.ShardedNode `json:"node"`
+	// Drained nodes are ineligible for new selections but are left in the
+	// registry so in-flight segment uploads to them can still complete.
+	Drained bool `json:"drained"`
+}
+
+// Registry is a file-backed store of NodeStates, safe for concurrent use
+// within a single process.
+type Registry struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Open returns a Registry backed by the JSON file at path. The file is
+// created on first write if it doesn't already exist.
+func Open(path string) *Registry {
+	return &Registry{path: path}
+}
+
+func (r *Registry) load() ([]*NodeState, error) {
+	data, err := os.ReadFile(r.path)
+	if os.IsNotExist(err) {
+		return []*NodeState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry file: %w", err)
+	}
+
+	var states []*NodeState
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal registry file: %w", err)
+	}
+
+	return states, nil
+}
+
+func (r *Registry) save(states []*NodeState) error {
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal registry: %w", err)
+	}
+
+	return os.WriteFile(r.path, data, 0644)
+}
+
+// List returns every registered node, including drained ones.
+func (r *Registry) List() ([]*NodeState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.load()
+}
+
+// Active returns every non-drained registered node, suitable for
+// shard.Select.
+func (r *Registry) Active() ([]*shard.ShardedNode, error) {
+	states, err := r.List()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*shard.ShardedNode, 0, len(states))
+	for _, state := range states {
+		if !state.Drained {
+			nodes = append(nodes, state.Node)
+		}
+	}
+
+	return nodes, nil
+}
+
+// AddNode registers a new node, or replaces the existing entry for the same
+// URL.
+func (r *Registry) AddNode(node *shard.ShardedNode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	states, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	for _, state := range states {
+		if state.Node.URL == node.URL {
+			state.Node = node
+			state.Drained = false
+			return r.save(states)
+		}
+	}
+
+	return r.save(append(states, &NodeState{Node: node}))
+}
+
+// DrainNode marks a node ineligible for new selections, without removing it
+// from the registry, so in-flight uploads to it can finish.
+func (r *Registry) DrainNode(url string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	states, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	for _, state := range states {
+		if state.Node.URL == url {
+			state.Drained = true
+			return r.save(states)
+		}
+	}
+
+	return fmt.Errorf("node %s is not registered", url)
+}
+
+// RemoveNode removes a node from the registry. Unless force is true, it
+// refuses the removal via shard.CheckReplica when doing so would drop any
+// shard below expectedReplica among the remaining active nodes.
+func (r *Registry) RemoveNode(url string, segNum uint64, expectedReplica uint, force bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	states, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]*NodeState, 0, len(states))
+	var removed bool
+	remainingConfigs := make([]*shard.ShardConfig, 0, len(states))
+	for _, state := range states {
+		if state.Node.URL == url {
+			removed = true
+			continue
+		}
+		remaining = append(remaining, state)
+		if !state.Drained {
+			remainingConfigs = append(remainingConfigs, &state.Node.Config)
+		}
+	}
+
+	if !removed {
+		return fmt.Errorf("node %s is not registered", url)
+	}
+
+	if !force && !shard.CheckReplica(segNum, remainingConfigs, expectedReplica) {
+		return fmt.Errorf("removing %s would drop a shard below the expected replica count of %d; use --force to override", url, expectedReplica)
+	}
+
+	return r.save(remaining)
+}
@@ -74,9 +74,8 @@ func (node *shardSegmentTreeNode) insert(numShard uint, shardId uint, expectedRe
 // select a set of given sharded node and make the data is replicated at least expctedReplica times
 // return the selected nodes and if selection is successful
 func Select(segNum uint64, nodes []*ShardedNode, expectedReplica uint, random bool) ([]*ShardedNode, bool) {
-	selected := make([]*ShardedNode, 0)
 	if expectedReplica == 0 {
-		return selected, true
+		return make([]*ShardedNode, 0), true
 	}
 	if random {
 		// shuffle
@@ -95,6 +94,94 @@ func Select(segNum uint64, nodes []*ShardedNode, expectedReplica uint, random bo
 		})
 
 	}
+	selected, _, ok := selectFromSorted(segNum, nodes, expectedReplica)
+	if !ok {
+		// preserve Select's original contract: failure always yields an empty
+		// selection. The partial list is only surfaced through SelectWeighted.
+		return make([]*ShardedNode, 0), false
+	}
+	return selected, ok
+}
+
+// SelectionPolicy customizes SelectWeighted: how it breaks ties between nodes
+// whose shard coverage is otherwise equivalent, and how it behaves when
+// expectedReplica cannot be fully met.
+type SelectionPolicy struct {
+	// Weighted enables latency- and freshness-aware scoring: among nodes that
+	// cover the same (numShard, shardId) slot, the lowest-score node is
+	// preferred instead of the first one encountered in shard-size order.
+	Weighted bool
+	// TTL is the freshness window in seconds used to compute the staleness
+	// penalty. Ignored when Weighted is false, or when TTL <= 0.
+	TTL int64
+	// BestEffortDegrade makes SelectWeighted return the best achievable
+	// selection, along with a DegradeReport, instead of failing outright when
+	// expectedReplica cannot be fully met.
+	BestEffortDegrade bool
+}
+
+// DegradeReport describes, for a best-effort selection, which shard ids ended
+// up with fewer than the requested replicas.
+type DegradeReport struct {
+	// UnderReplicated maps shard id to the replica count actually achieved.
+	UnderReplicated map[uint64]uint
+}
+
+// score combines RPC latency with a staleness penalty, so that slow or
+// long-unrefreshed nodes are deprioritized during selection.
+func score(node *ShardedNode, ttl int64, now int64) float64 {
+	if ttl <= 0 {
+		return float64(node.Latency)
+	}
+	age := now - node.Since
+	stalenessRatio := float64(max(int64(0), age-ttl)) / float64(ttl)
+	return float64(node.Latency) * (1 + stalenessRatio)
+}
+
+// SelectWeighted is a latency- and freshness-aware variant of Select: nodes
+// whose shard coverage is equivalent in insertion order are additionally
+// ranked by score (see SelectionPolicy), and selection can optionally degrade
+// instead of fail when expectedReplica cannot be fully met.
+func SelectWeighted(segNum uint64, nodes []*ShardedNode, expectedReplica uint, policy SelectionPolicy) ([]*ShardedNode, *DegradeReport, bool) {
+	if expectedReplica == 0 {
+		return make([]*ShardedNode, 0), nil, true
+	}
+
+	now := time.Now().Unix()
+	sorted := make([]*ShardedNode, len(nodes))
+	copy(sorted, nodes)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Config.NumShard != sorted[j].Config.NumShard {
+			return sorted[i].Config.NumShard < sorted[j].Config.NumShard
+		}
+		if sorted[i].Config.ShardId != sorted[j].Config.ShardId {
+			return sorted[i].Config.ShardId < sorted[j].Config.ShardId
+		}
+		if !policy.Weighted {
+			return false
+		}
+		return score(sorted[i], policy.TTL, now) < score(sorted[j], policy.TTL, now)
+	})
+
+	selected, occupied, ok := selectFromSorted(segNum, sorted, expectedReplica)
+	if ok || !policy.BestEffortDegrade {
+		return selected, nil, ok
+	}
+
+	report := &DegradeReport{UnderReplicated: make(map[uint64]uint)}
+	for id := uint64(0); id < segNum; id++ {
+		if occupied[id] < expectedReplica {
+			report.UnderReplicated[id] = occupied[id]
+		}
+	}
+	return selected, report, false
+}
+
+// selectFromSorted runs the segment-tree insertion over pre-sorted nodes and
+// reports, for the segNum > 0 case, how many replicas were achieved per shard
+// id so that callers can build a DegradeReport on partial success.
+func selectFromSorted(segNum uint64, nodes []*ShardedNode, expectedReplica uint) ([]*ShardedNode, map[uint64]uint, bool) {
+	selected := make([]*ShardedNode, 0)
 	// build segment tree to select proper nodes by shard configs
 	root := shardSegmentTreeNode{
 		numShard: 1,
@@ -111,7 +198,7 @@ func Select(segNum uint64, nodes []*ShardedNode, expectedReplica uint, random bo
 			selected = append(selected, node)
 		}
 		if root.replica >= expectedReplica {
-			return selected, true
+			return selected, occupied, true
 		}
 		if segNum > 0 {
 			chosen := false
@@ -130,11 +217,14 @@ func Select(segNum uint64, nodes []*ShardedNode, expectedReplica uint, random bo
 				occupiedNodes = append(occupiedNodes, node)
 			}
 			if uint64(hit) == segNum*uint64(expectedReplica) {
-				return occupiedNodes, true
+				return occupiedNodes, occupied, true
 			}
 		}
 	}
-	return make([]*ShardedNode, 0), false
+	if segNum > 0 {
+		return occupiedNodes, occupied, false
+	}
+	return make([]*ShardedNode, 0), occupied, false
 }
 
 func CheckReplica(segNum uint64, shardConfigs []*ShardConfig, expectedReplica uint) bool {
@@ -0,0 +1,78 @@
+package shard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScore(t *testing.T) {
+	node := &ShardedNode{Latency: 100, Since: 1000}
+
+	// TTL disabled: score is just the raw latency.
+	assert.Equal(t, float64(100), score(node, 0, 2000))
+
+	// Within the freshness window: no staleness penalty.
+	assert.Equal(t, float64(100), score(node, 500, 1400))
+
+	// Past the freshness window: penalized proportionally to staleness.
+	assert.Equal(t, float64(200), score(node, 500, 2000))
+}
+
+func TestSelectWeightedPrefersLowerScoreOnTie(t *testing.T) {
+	fast := &ShardedNode{URL: "fast", Config: ShardConfig{NumShard: 2, ShardId: 0}, Latency: 10}
+	slow := &ShardedNode{URL: "slow", Config: ShardConfig{NumShard: 2, ShardId: 0}, Latency: 1000}
+	other := &ShardedNode{URL: "other", Config: ShardConfig{NumShard: 2, ShardId: 1}, Latency: 10}
+
+	selected, report, ok := SelectWeighted(2, []*ShardedNode{slow, fast, other}, 1, SelectionPolicy{Weighted: true})
+	assert.True(t, ok)
+	assert.Nil(t, report)
+
+	urls := make([]string, len(selected))
+	for i, node := range selected {
+		urls[i] = node.URL
+	}
+	assert.Contains(t, urls, "fast")
+	assert.NotContains(t, urls, "slow")
+}
+
+func TestSelectWeightedIgnoresScoreWhenNotWeighted(t *testing.T) {
+	first := &ShardedNode{URL: "first", Config: ShardConfig{NumShard: 2, ShardId: 0}, Latency: 1000}
+	second := &ShardedNode{URL: "second", Config: ShardConfig{NumShard: 2, ShardId: 0}, Latency: 10}
+	other := &ShardedNode{URL: "other", Config: ShardConfig{NumShard: 2, ShardId: 1}, Latency: 10}
+
+	// Without Weighted, ties break by insertion order, not score, so the
+	// first node of an equivalent shard slot wins regardless of latency.
+	selected, _, ok := SelectWeighted(2, []*ShardedNode{first, second, other}, 1, SelectionPolicy{})
+	assert.True(t, ok)
+	assert.Len(t, selected, 2)
+	assert.Equal(t, "first", selected[0].URL)
+}
+
+func TestSelectWeightedDegradeReport(t *testing.T) {
+	// A single node covering only shard id 0 of 4 can't satisfy a replica of
+	// 2 across all 4 segments; with BestEffortDegrade it should report every
+	// shard id as under-replicated instead of failing outright.
+	node := &ShardedNode{URL: "node", Config: ShardConfig{NumShard: 4, ShardId: 0}}
+
+	selected, report, ok := SelectWeighted(4, []*ShardedNode{node}, 2, SelectionPolicy{BestEffortDegrade: true})
+	assert.False(t, ok)
+	assert.NotNil(t, report)
+	assert.Len(t, selected, 1)
+	assert.Equal(t, uint(1), report.UnderReplicated[0])
+	assert.Equal(t, uint(0), report.UnderReplicated[1])
+	assert.Equal(t, uint(0), report.UnderReplicated[2])
+	assert.Equal(t, uint(0), report.UnderReplicated[3])
+}
+
+func TestSelectWeightedFailsWithoutDegrade(t *testing.T) {
+	node := &ShardedNode{URL: "node", Config: ShardConfig{NumShard: 4, ShardId: 0}}
+
+	// Without BestEffortDegrade, a failed selection still reports no
+	// DegradeReport, even though the underlying best-achievable node list is
+	// the same as the degraded case.
+	selected, report, ok := SelectWeighted(4, []*ShardedNode{node}, 2, SelectionPolicy{})
+	assert.False(t, ok)
+	assert.Nil(t, report)
+	assert.Len(t, selected, 1)
+}
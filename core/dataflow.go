@@ -36,9 +36,12 @@ type IterableData interface {
 	Read(buf []byte, offset int64) (int, error)
 }
 
-// MerkleTree create merkle tree of the data.
+// MerkleTree create merkle tree of the data. It buffers every segment hash in
+// memory; use MerkleTreeCompact instead when only the root (not the full
+// tree) is needed, for O(log N) memory regardless of file size.
 func MerkleTree(data IterableData) (*merkle.Tree, error) {
 	var builder merkle.TreeBuilder
+
 	initializer := &TreeBuilderInitializer{
 		data:    data,
 		offset:  0,
@@ -54,6 +57,31 @@ func MerkleTree(data IterableData) (*merkle.Tree, error) {
 	return builder.Build(), nil
 }
 
+// MerkleTreeCompact builds a merkle.CompactTree of the data by appending one
+// segment root at a time, instead of buffering every segment hash like
+// MerkleTree does. Memory use is bounded to O(log N) regardless of file
+// size, and the resulting tree can be persisted between runs via
+// CompactTree.Save so an interrupted upload can resume without re-reading
+// earlier segments.
+func MerkleTreeCompact(data IterableData) (*merkle.CompactTree, error) {
+	tree := merkle.NewCompactTree()
+
+	segments := NumSegmentsPadded(data)
+	for i := 0; i < segments; i++ {
+		offset := int64(i) * DefaultSegmentSize
+		buf, err := ReadAt(data, DefaultSegmentSize, offset, data.PaddedSize())
+		if err != nil {
+			return nil, err
+		}
+
+		if err := tree.Append(SegmentRoot(buf)); err != nil {
+			return nil, err
+		}
+	}
+
+	return tree, nil
+}
+
 func NumSplits(total int64, unit int) uint64 {
 	return uint64((total-1)/int64(unit) + 1)
 }
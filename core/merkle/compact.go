@@ -0,0 +1,173 @@
+package merkle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// CompactTree is a streaming Merkle tree builder that keeps only a stack of
+// perfect-subtree roots indexed by level, instead of buffering every leaf
+// hash. Append is amortized O(1) and memory use is O(log N) regardless of
+// leaf count.
+type CompactTree struct {
+	// Levels[i] holds the root of a completed perfect subtree of 2^i leaves
+	// that hasn't yet been merged into a larger subtree, or the zero hash if
+	// there is no such pending subtree at that level.
+	Levels []common.Hash `json:"levels"`
+	// Spine holds, for each level, the sibling hash needed to prove the
+	// still-open (incomplete) subtree at that level. Spine[i] is valid only
+	// while level i is open, i.e. has received a leaf but hasn't completed a
+	// perfect subtree of its own yet.
+	Spine []common.Hash `json:"spine"`
+	// Count is the number of leaves appended so far.
+	Count uint64 `json:"count"`
+}
+
+// NewCompactTree returns an empty CompactTree.
+func NewCompactTree() *CompactTree {
+	return &CompactTree{}
+}
+
+// hashNode combines a left and right child hash into their parent hash.
+func hashNode(left, right common.Hash) common.Hash {
+	return crypto.Keccak256Hash(left.Bytes(), right.Bytes())
+}
+
+// Append adds a leaf hash to the tree in amortized O(1), carrying completed
+// perfect subtrees up through Levels the same way a binary counter carries
+// bits.
+func (t *CompactTree) Append(leaf common.Hash) error {
+	if len(t.Spine) == 0 {
+		t.Spine = append(t.Spine, common.Hash{})
+	}
+	t.Spine[0] = leaf
+
+	hash := leaf
+	level := 0
+	for {
+		if level >= len(t.Levels) {
+			t.Levels = append(t.Levels, common.Hash{})
+		}
+		if t.Levels[level] == (common.Hash{}) {
+			t.Levels[level] = hash
+			break
+		}
+
+		// merge with the pending subtree at this level and carry up
+		if level+1 >= len(t.Spine) {
+			t.Spine = append(t.Spine, common.Hash{})
+		}
+		hash = hashNode(t.Levels[level], hash)
+		t.Spine[level+1] = t.Levels[level]
+		t.Levels[level] = common.Hash{}
+		level++
+	}
+
+	t.Count++
+	return nil
+}
+
+// Root folds the level stack into a single root, padding missing leaves at
+// each level with emptyHash so that the result is as if the tree had been
+// built over a power-of-two-padded leaf count.
+//
+// Levels[i] holds the root of a perfect subtree positioned immediately to the
+// left of everything folded so far, so an unset level between two set levels
+// represents a real gap that must be padded up to the set level's size before
+// it can be folded in - it is not simply skipped.
+func (t *CompactTree) Root(emptyHash common.Hash) common.Hash {
+	if t.Count == 0 {
+		return emptyHash
+	}
+
+	pad := make([]common.Hash, len(t.Levels)+1)
+	pad[0] = emptyHash
+	for i := 1; i < len(pad); i++ {
+		pad[i] = hashNode(pad[i-1], pad[i-1])
+	}
+
+	var acc common.Hash
+	haveAcc := false
+	accLevel := 0
+
+	for level, levelHash := range t.Levels {
+		if levelHash == (common.Hash{}) {
+			continue
+		}
+		if !haveAcc {
+			acc = levelHash
+			accLevel = level
+			haveAcc = true
+			continue
+		}
+
+		for accLevel < level {
+			acc = hashNode(acc, pad[accLevel])
+			accLevel++
+		}
+		acc = hashNode(levelHash, acc)
+		accLevel = level + 1
+	}
+
+	return acc
+}
+
+// InclusionProof returns the sibling hashes from leafIndex up to the root of
+// the perfect subtree (the Levels entry) that the leaf was last merged into,
+// for a leaf that is still on the right spine, i.e. hasn't yet been folded
+// into a completed perfect subtree. That subtree root is the tree's Root only
+// when Count is a power of two; the other Levels entries and the empty-hash
+// padding that Root folds in are not part of the proof. Leaves in
+// already-completed subtrees require the full subtree to re-derive a proof
+// and are not supported here.
+//
+// leafIndex's 0-based position, before it was appended, carries the same way
+// a binary counter carries: the number of trailing 1-bits in leafIndex is
+// exactly the number of levels the leaf's hash was merged up through, and
+// Spine[level+1] holds the sibling it was merged with at each of those
+// levels.
+func (t *CompactTree) InclusionProof(leafIndex uint64) ([]common.Hash, error) {
+	if leafIndex >= t.Count {
+		return nil, fmt.Errorf("leaf index %d out of range: tree has %d leaves", leafIndex, t.Count)
+	}
+	if leafIndex != t.Count-1 {
+		return nil, fmt.Errorf("leaf index %d is not on the right spine: only the most recently appended leaf (%d) has a cached proof", leafIndex, t.Count-1)
+	}
+
+	proof := make([]common.Hash, 0, len(t.Spine))
+	for level, carried := 0, leafIndex; carried&1 == 1; level, carried = level+1, carried>>1 {
+		proof = append(proof, t.Spine[level+1])
+	}
+
+	return proof, nil
+}
+
+// Save persists the tree state to path so a later process can resume
+// appending without re-reading earlier leaves.
+func (t *CompactTree) Save(path string) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("failed to marshal compact tree: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadCompactTree restores a CompactTree previously persisted with Save.
+func LoadCompactTree(path string) (*CompactTree, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compact tree state: %w", err)
+	}
+
+	var tree CompactTree
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal compact tree state: %w", err)
+	}
+
+	return &tree, nil
+}
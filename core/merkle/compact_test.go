@@ -0,0 +1,105 @@
+package merkle
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// naiveRoot builds the reference padded-tree root by hashing leaves bottom-up
+// after padding the leaf count up to the next power of two with emptyHash.
+func naiveRoot(leaves []common.Hash, emptyHash common.Hash) common.Hash {
+	n := 1
+	for n < len(leaves) {
+		n <<= 1
+	}
+
+	level := make([]common.Hash, n)
+	copy(level, leaves)
+	for i := len(leaves); i < n; i++ {
+		level[i] = emptyHash
+	}
+
+	for len(level) > 1 {
+		next := make([]common.Hash, len(level)/2)
+		for i := range next {
+			next[i] = hashNode(level[2*i], level[2*i+1])
+		}
+		level = next
+	}
+
+	return level[0]
+}
+
+func TestCompactTreeRoot(t *testing.T) {
+	emptyHash := common.HexToHash("0xdead")
+
+	for _, count := range []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 16} {
+		tree := NewCompactTree()
+		leaves := make([]common.Hash, count)
+		for i := 0; i < count; i++ {
+			leaves[i] = crypto.Keccak256Hash([]byte{byte(i)})
+			assert.NoError(t, tree.Append(leaves[i]))
+		}
+
+		assert.Equal(t, naiveRoot(leaves, emptyHash), tree.Root(emptyHash), "count=%d", count)
+	}
+}
+
+// reconstructRoot folds an InclusionProof back up to a root the same way
+// Append carried hashes up: each proof entry is the older sibling subtree
+// that the running candidate was merged under.
+func reconstructRoot(leaf common.Hash, proof []common.Hash) common.Hash {
+	candidate := leaf
+	for _, sibling := range proof {
+		candidate = hashNode(sibling, candidate)
+	}
+	return candidate
+}
+
+func TestCompactTreeInclusionProofPowerOfTwo(t *testing.T) {
+	emptyHash := common.HexToHash("0xdead")
+
+	for _, count := range []int{1, 2, 4, 8, 16} {
+		tree := NewCompactTree()
+		var last common.Hash
+		for i := 0; i < count; i++ {
+			last = crypto.Keccak256Hash([]byte{byte(i)})
+			assert.NoError(t, tree.Append(last))
+		}
+
+		proof, err := tree.InclusionProof(uint64(count - 1))
+		assert.NoError(t, err, "count=%d", count)
+		assert.Equal(t, tree.Root(emptyHash), reconstructRoot(last, proof), "count=%d", count)
+	}
+}
+
+func TestCompactTreeInclusionProofLoneLeaf(t *testing.T) {
+	// A leaf that hasn't been merged into any pending subtree yet (leafIndex
+	// has no trailing 1 bits) has no siblings to report.
+	for _, count := range []int{3, 5, 7, 9} {
+		tree := NewCompactTree()
+		for i := 0; i < count; i++ {
+			assert.NoError(t, tree.Append(crypto.Keccak256Hash([]byte{byte(i)})))
+		}
+
+		proof, err := tree.InclusionProof(uint64(count - 1))
+		assert.NoError(t, err, "count=%d", count)
+		assert.Empty(t, proof, "count=%d", count)
+	}
+}
+
+func TestCompactTreeInclusionProofErrors(t *testing.T) {
+	tree := NewCompactTree()
+	assert.NoError(t, tree.Append(crypto.Keccak256Hash([]byte{0})))
+	assert.NoError(t, tree.Append(crypto.Keccak256Hash([]byte{1})))
+	assert.NoError(t, tree.Append(crypto.Keccak256Hash([]byte{2})))
+
+	_, err := tree.InclusionProof(3)
+	assert.Error(t, err, "out of range leaf index should error")
+
+	_, err = tree.InclusionProof(0)
+	assert.Error(t, err, "non-last leaf index should error")
+}